@@ -0,0 +1,210 @@
+// Package doctype is a runtime registry of document types. Each type is
+// described by its fields (name, JSON type, whether it's required, and an
+// optional regex the value must match) rather than a compiled-in Go struct,
+// so new types (W-2, 1099, invoices, ...) can be added via the
+// /document-types endpoint without recompiling. The LLM prompt is generated
+// from a type's fields, and a parsed response is validated against the same
+// fields before it's returned to the caller.
+package doctype
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FieldType is the JSON type a Field's value must have.
+type FieldType string
+
+const (
+	FieldTypeString  FieldType = "string"
+	FieldTypeBoolean FieldType = "boolean"
+	FieldTypeNumber  FieldType = "number"
+	FieldTypeInteger FieldType = "integer"
+)
+
+// Field describes a single field of a document type's schema.
+type Field struct {
+	Name     string    `json:"name"`
+	Type     FieldType `json:"type"`
+	Required bool      `json:"required,omitempty"`
+	// Pattern, if set, is a regular expression a string field's value must
+	// match, e.g. `^\$\d+\.\d{2}$` for Form 941's money fields.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// Type is a registered document type.
+type Type struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Fields      []Field `json:"fields"`
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Type{}
+)
+
+// Register validates t and adds it to the registry, replacing any existing
+// type of the same name so a corrected schema can be re-submitted without a
+// restart.
+func Register(t Type) error {
+	if t.Name == "" {
+		return fmt.Errorf("doctype: name is required")
+	}
+	if len(t.Fields) == 0 {
+		return fmt.Errorf("doctype: at least one field is required")
+	}
+
+	for _, f := range t.Fields {
+		if f.Name == "" {
+			return fmt.Errorf("doctype: field name is required")
+		}
+		switch f.Type {
+		case FieldTypeString, FieldTypeBoolean, FieldTypeNumber, FieldTypeInteger:
+		default:
+			return fmt.Errorf("doctype: field %q has unsupported type %q", f.Name, f.Type)
+		}
+		if f.Pattern != "" {
+			if _, err := regexp.Compile(f.Pattern); err != nil {
+				return fmt.Errorf("doctype: field %q has invalid pattern: %w", f.Name, err)
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	registry[t.Name] = t
+	return nil
+}
+
+// Get returns the registered type named name.
+func Get(name string) (Type, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	t, ok := registry[name]
+	return t, ok
+}
+
+// List returns every registered type, ordered by name.
+func List() []Type {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	types := make([]Type, 0, len(registry))
+	for _, t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Prompt builds the LLM instruction for t covering pages startPage-endPage
+// of a totalPages-page document (pass startPage=1, endPage=totalPages for a
+// document sent in a single shot). It is the registered description (if
+// any), followed by the JSON shape derived from its fields: each field is
+// requested as an object of {value, confidence} when totalPages is 1, or
+// {value, confidence, source_page} when totalPages is more than 1 (whether
+// the document is sent whole or split across multiple chunks), so
+// extractions can be merged by confidence and a value traced back to the
+// page it came from. The page range is likewise mentioned only when
+// totalPages is more than 1.
+func (t Type) Prompt(startPage, endPage, totalPages int) string {
+	var b strings.Builder
+
+	if t.Description != "" {
+		b.WriteString(t.Description)
+		b.WriteString("\n\n")
+	}
+
+	if totalPages > 1 {
+		fmt.Fprintf(&b, "This file contains pages %d-%d of a %d-page document. ", startPage, endPage, totalPages)
+		b.WriteString("Only extract fields you can actually find within these pages; for fields not present here, return an empty value and a confidence of 0.\n\n")
+	}
+
+	if totalPages > 1 {
+		b.WriteString("Return ONLY a valid JSON object with the following structure. For each field, return an object with \"value\" (the extracted value), \"confidence\" (your confidence in the extraction, from 0 to 1), and \"source_page\" (the absolute page number within the document where you found it):\n{\n")
+	} else {
+		b.WriteString("Return ONLY a valid JSON object with the following structure. For each field, return an object with \"value\" (the extracted value) and \"confidence\" (your confidence in the extraction, from 0 to 1):\n{\n")
+	}
+	for i, f := range t.Fields {
+		comma := ","
+		if i == len(t.Fields)-1 {
+			comma = ""
+		}
+		if totalPages > 1 {
+			fmt.Fprintf(&b, "  %q: {\"value\": %s, \"confidence\": 0.0, \"source_page\": %d}%s\n", f.Name, placeholder(f), startPage, comma)
+		} else {
+			fmt.Fprintf(&b, "  %q: {\"value\": %s, \"confidence\": 0.0}%s\n", f.Name, placeholder(f), comma)
+		}
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("Do not include any explanations, markdown formatting, or additional text outside the JSON object.\n")
+	b.WriteString("If you cannot find a specific field, use an empty string (or false for booleans) as its value and a confidence of 0.")
+
+	return b.String()
+}
+
+func placeholder(f Field) string {
+	switch f.Type {
+	case FieldTypeBoolean:
+		return "true or false"
+	case FieldTypeNumber, FieldTypeInteger:
+		return "0"
+	default:
+		if f.Pattern != "" {
+			return fmt.Sprintf("%q", "value matching "+f.Pattern)
+		}
+		return `""`
+	}
+}
+
+// Validate checks a parsed response's fields against t: required fields
+// must be present, values must match their declared JSON type, and string
+// fields with a Pattern must match it. It returns one message per
+// violation, or nil if fields satisfies the schema.
+func (t Type) Validate(fields map[string]interface{}) []string {
+	var errs []string
+
+	for _, f := range t.Fields {
+		value, ok := fields[f.Name]
+		if !ok || value == nil {
+			if f.Required {
+				errs = append(errs, fmt.Sprintf("missing required field %q", f.Name))
+			}
+			continue
+		}
+
+		switch f.Type {
+		case FieldTypeString:
+			s, ok := value.(string)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("field %q must be a string", f.Name))
+				continue
+			}
+			if f.Pattern != "" {
+				if matched, err := regexp.MatchString(f.Pattern, s); err == nil && !matched {
+					errs = append(errs, fmt.Sprintf("field %q value %q does not match pattern %q", f.Name, s, f.Pattern))
+				}
+			}
+		case FieldTypeBoolean:
+			if _, ok := value.(bool); !ok {
+				errs = append(errs, fmt.Sprintf("field %q must be a boolean", f.Name))
+			}
+		case FieldTypeNumber, FieldTypeInteger:
+			n, ok := value.(float64)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("field %q must be a number", f.Name))
+				continue
+			}
+			if f.Type == FieldTypeInteger && n != math.Trunc(n) {
+				errs = append(errs, fmt.Sprintf("field %q must be an integer", f.Name))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,118 @@
+package doctype
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterRejectsInvalidTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  Type
+	}{
+		{"missing name", Type{Fields: []Field{{Name: "x", Type: FieldTypeString}}}},
+		{"no fields", Type{Name: "empty"}},
+		{"field missing name", Type{Name: "t", Fields: []Field{{Type: FieldTypeString}}}},
+		{"unsupported field type", Type{Name: "t", Fields: []Field{{Name: "x", Type: "object"}}}},
+		{"invalid pattern", Type{Name: "t", Fields: []Field{{Name: "x", Type: FieldTypeString, Pattern: "("}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Register(tt.typ); err == nil {
+				t.Errorf("Register(%+v) = nil, want error", tt.typ)
+			}
+		})
+	}
+}
+
+func TestRegisterGetList(t *testing.T) {
+	typ := Type{
+		Name: "w2",
+		Fields: []Field{
+			{Name: "wages", Type: FieldTypeString, Required: true},
+		},
+	}
+
+	if err := Register(typ); err != nil {
+		t.Fatalf("Register() returned error: %v", err)
+	}
+
+	got, ok := Get("w2")
+	if !ok {
+		t.Fatal("Get(\"w2\") not found after Register")
+	}
+	if len(got.Fields) != 1 || got.Fields[0].Name != "wages" {
+		t.Errorf("Get(\"w2\") = %+v, want the registered fields", got)
+	}
+
+	found := false
+	for _, typ := range List() {
+		if typ.Name == "w2" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("List() missing \"w2\" after Register")
+	}
+}
+
+func TestPromptRendersBooleanUnquoted(t *testing.T) {
+	typ := Type{
+		Name: "form_941",
+		Fields: []Field{
+			{Name: "Box 4", Type: FieldTypeBoolean},
+		},
+	}
+
+	prompt := typ.Prompt(1, 1, 1)
+	if !strings.Contains(prompt, `"value": true or false`) {
+		t.Errorf("Prompt() = %q, want it to render Box 4's value as `true or false`", prompt)
+	}
+}
+
+func TestPromptMentionsPageRangeOnlyForMultiPageDocuments(t *testing.T) {
+	typ := Type{
+		Name:   "form_941",
+		Fields: []Field{{Name: "EIN", Type: FieldTypeString}},
+	}
+
+	single := typ.Prompt(1, 1, 1)
+	if strings.Contains(single, "page") {
+		t.Errorf("Prompt(1, 1, 1) = %q, want no page-range mention for a single-chunk document", single)
+	}
+
+	chunked := typ.Prompt(5, 8, 20)
+	if !strings.Contains(chunked, "pages 5-8 of a 20-page document") {
+		t.Errorf("Prompt(5, 8, 20) = %q, want it to mention the page range", chunked)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	typ := Type{
+		Name: "form_941",
+		Fields: []Field{
+			{Name: "EIN", Type: FieldTypeString, Required: true, Pattern: `^\d{9}$`},
+			{Name: "Box 4", Type: FieldTypeBoolean},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		want   int
+	}{
+		{"valid", map[string]interface{}{"EIN": "123456789", "Box 4": true}, 0},
+		{"missing required", map[string]interface{}{"Box 4": true}, 1},
+		{"pattern mismatch", map[string]interface{}{"EIN": "not-an-ein", "Box 4": true}, 1},
+		{"wrong type", map[string]interface{}{"EIN": "123456789", "Box 4": "yes"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typ.Validate(tt.fields); len(got) != tt.want {
+				t.Errorf("Validate(%+v) = %v, want %d error(s)", tt.fields, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Package httpx is a small middleware-chain HTTP layer: a Middleware type,
+// a Chain helper to compose them, and the built-in middlewares (request-id
+// propagation, structured access logging, panic recovery, CORS, API-key
+// auth, and per-IP rate limiting) main.go's handlers are wrapped in instead
+// of each repeating its own CORS/auth boilerplate.
+package httpx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Middleware wraps an http.Handler with additional behavior.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies mws to h in order, so the first middleware in mws is the
+// outermost: Chain(h, A, B) behaves like A(B(h)).
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// RequestIDFromContext returns the request id the RequestID middleware
+// stored on ctx, or "" if RequestID hasn't run.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestID generates a random id for every request, stores it on the
+// request's context for downstream handlers and middlewares, and echoes it
+// back as an X-Request-ID header so a parse failure can be traced
+// end-to-end through the logs.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+	})
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unavailable"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// CORS sets permissive CORS headers allowing methods (plus OPTIONS) and
+// answers preflight OPTIONS requests directly instead of passing them to
+// the wrapped handler.
+func CORS(methods string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.Header().Set("Access-Control-Allow-Methods", methods+", OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, X-API-Key")
+
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// statusRecorder captures the status code a handler wrote so AccessLog can
+// report it after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// AccessLog logs one structured JSON line per request: method, path,
+// status, duration in milliseconds, and the request id set by RequestID
+// (empty if RequestID didn't run first).
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		entry, err := json.Marshal(map[string]interface{}{
+			"method":      r.Method,
+			"path":        r.URL.Path,
+			"status":      rec.status,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"request_id":  RequestIDFromContext(r.Context()),
+		})
+		if err != nil {
+			log.Printf("httpx: error encoding access log entry: %v", err)
+			return
+		}
+		log.Println(string(entry))
+	})
+}
+
+// Recover catches panics from downstream handlers, logs them with the
+// request id, and responds 500 instead of crashing the server.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("panic handling %s %s [request_id=%s]: %v", r.Method, r.URL.Path, RequestIDFromContext(r.Context()), rec)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// APIKeyAuth requires the X-API-Key header to equal apiKey. If apiKey is
+// empty, auth is disabled and every request is let through — callers
+// should only wire this in when an API key is actually configured.
+func APIKeyAuth(apiKey string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey == "" || r.Header.Get("X-API-Key") == apiKey {
+				next.ServeHTTP(w, r)
+				return
+			}
+			http.Error(w, "Invalid or missing X-API-Key", http.StatusUnauthorized)
+		})
+	}
+}
+
+// RateLimit allows at most requestsPerMinute requests per client IP per
+// fixed one-minute window, tracked in memory. A client's window starts at
+// its first request and is replaced by a new one once a minute has passed,
+// so up to twice the limit can land within less than a minute across a
+// window boundary. It's meant for a single-process deployment; a
+// multi-instance deployment would need a shared store.
+func RateLimit(requestsPerMinute int) Middleware {
+	type window struct {
+		start time.Time
+		count int
+	}
+
+	var mu sync.Mutex
+	windows := map[string]*window{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r)
+			now := time.Now()
+
+			mu.Lock()
+			win, ok := windows[ip]
+			if !ok || now.Sub(win.start) >= time.Minute {
+				win = &window{start: now}
+				windows[ip] = win
+			}
+			win.count++
+			exceeded := win.count > requestsPerMinute
+			mu.Unlock()
+
+			if exceeded {
+				http.Error(w, "Rate limit exceeded, try again later", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP extracts the request's client IP, preferring the first non-empty
+// entry of an X-Forwarded-For header (set by the reverse proxies this is
+// expected to sit behind) over the raw connection address. The entry is
+// used as is; it isn't checked to be a valid IP address.
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		if ip := strings.TrimSpace(strings.Split(fwd, ",")[0]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
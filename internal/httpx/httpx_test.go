@@ -0,0 +1,155 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChainOrdersOutermostFirst(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), record("A"), record("B"))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"A", "B", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("Chain() call order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Chain() call order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestCORSAnswersPreflightWithoutCallingNext(t *testing.T) {
+	called := false
+	h := CORS("POST")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/", nil))
+
+	if called {
+		t.Error("CORS() called next on an OPTIONS request, want it handled directly")
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("CORS() OPTIONS status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "POST, OPTIONS" {
+		t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, "POST, OPTIONS")
+	}
+}
+
+func TestRequestIDSetsHeaderAndContext(t *testing.T) {
+	var gotID string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = RequestIDFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	headerID := rec.Header().Get("X-Request-ID")
+	if headerID == "" {
+		t.Fatal("RequestID() did not set X-Request-ID header")
+	}
+	if gotID != headerID {
+		t.Errorf("RequestIDFromContext() = %q, want it to match the X-Request-ID header %q", gotID, headerID)
+	}
+}
+
+func TestRecoverConvertsPanicToStatus500(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Recover() status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	h := APIKeyAuth("secret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		headerKey  string
+		wantStatus int
+	}{
+		{"correct key", "secret", http.StatusOK},
+		{"wrong key", "nope", http.StatusUnauthorized},
+		{"missing key", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.headerKey != "" {
+				req.Header.Set("X-API-Key", tt.headerKey)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("APIKeyAuth() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthDisabledWhenUnconfigured(t *testing.T) {
+	h := APIKeyAuth("")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("APIKeyAuth(\"\") status = %d, want %d (auth disabled)", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRateLimitBlocksAfterThreshold(t *testing.T) {
+	h := RateLimit(2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.1:1234"
+		return req
+	}
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newReq())
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want %d", i, rec.Code, http.StatusOK)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newReq())
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("request 3 status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+}
@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// retryConfig controls WithRetry's exponential backoff.
+type retryConfig struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+var defaultRetryConfig = retryConfig{
+	maxAttempts: 4,
+	baseDelay:   500 * time.Millisecond,
+	maxDelay:    8 * time.Second,
+}
+
+// WithRetry calls fn, retrying transient failures (HTTP 429/5xx from any
+// Parser backend, see retryable) with exponential backoff and jitter between
+// attempts. It returns as soon as fn succeeds or returns a non-retryable
+// error, or after defaultRetryConfig.maxAttempts attempts have failed.
+func WithRetry(ctx context.Context, fn func() (string, error)) (string, error) {
+	cfg := defaultRetryConfig
+
+	var result string
+	var err error
+	for attempt := 0; attempt < cfg.maxAttempts; attempt++ {
+		result, err = fn()
+		if err == nil || !retryable(err) {
+			return result, err
+		}
+
+		if attempt == cfg.maxAttempts-1 {
+			break
+		}
+
+		delay := time.Duration(math.Min(float64(cfg.maxDelay), float64(cfg.baseDelay)*math.Pow(2, float64(attempt))))
+		delay += time.Duration(rand.Int63n(int64(delay/2) + 1))
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	return result, err
+}
@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryableStatusCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited REST error", &apiError{statusCode: 429}, true},
+		{"server REST error", &apiError{statusCode: 503}, true},
+		{"client REST error", &apiError{statusCode: 400}, false},
+		{"rate limited googleapi error", &googleapi.Error{Code: 429}, true},
+		{"server googleapi error", &googleapi.Error{Code: 500}, true},
+		{"client googleapi error", &googleapi.Error{Code: 404}, false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetrySucceedsAfterTransientFailures(t *testing.T) {
+	attempts := 0
+	result, err := WithRetry(context.Background(), func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", &apiError{statusCode: 503}
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("WithRetry() returned error: %v", err)
+	}
+	if result != "ok" {
+		t.Errorf("WithRetry() = %q, want %q", result, "ok")
+	}
+	if attempts != 3 {
+		t.Errorf("WithRetry() made %d attempts, want 3", attempts)
+	}
+}
+
+func TestWithRetryStopsOnNonRetryableError(t *testing.T) {
+	attempts := 0
+	_, err := WithRetry(context.Background(), func() (string, error) {
+		attempts++
+		return "", &apiError{statusCode: 400}
+	})
+	if err == nil {
+		t.Fatal("WithRetry() expected an error, got nil")
+	}
+	if attempts != 1 {
+		t.Errorf("WithRetry() made %d attempts, want 1 for a non-retryable error", attempts)
+	}
+}
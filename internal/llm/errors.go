@@ -0,0 +1,38 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// apiError is returned by the REST-backed parsers (openai, ollama) so
+// retryable can tell transient failures (429/5xx) apart from permanent ones.
+type apiError struct {
+	statusCode int
+	message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("llm: API error (status %d): %s", e.statusCode, e.message)
+}
+
+// retryable reports whether err looks like a transient failure worth
+// retrying: a rate limit (429) or server error (5xx). Gemini's SDK surfaces
+// these as a *googleapi.Error; the REST-backed parsers surface them as
+// *apiError.
+func retryable(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.statusCode == http.StatusTooManyRequests || apiErr.statusCode >= 500
+	}
+
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		return gErr.Code == http.StatusTooManyRequests || gErr.Code >= 500
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// geminiParser implements Parser on top of the Gemini multimodal API.
+type geminiParser struct {
+	client *genai.Client
+	model  string
+}
+
+func newGeminiParser(cfg Config) (Parser, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("llm/gemini: API key is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "gemini-2.0-flash"
+	}
+
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(cfg.APIKey))
+	if err != nil {
+		return nil, fmt.Errorf("llm/gemini: error creating client: %w", err)
+	}
+
+	return &geminiParser{client: client, model: model}, nil
+}
+
+func (p *geminiParser) Name() string {
+	return "gemini"
+}
+
+func (p *geminiParser) Parse(ctx context.Context, prompt string, file []byte, mimeType string) (string, error) {
+	model := p.client.GenerativeModel(p.model)
+	model.SetTemperature(0.0) // Deterministic responses
+
+	parts := []genai.Part{
+		genai.Text(prompt),
+		genai.Blob{MIMEType: mimeType, Data: file},
+	}
+
+	resp, err := model.GenerateContent(ctx, parts...)
+	if err != nil {
+		return "", fmt.Errorf("llm/gemini: error calling Gemini AI API: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("llm/gemini: no response from Gemini AI API")
+	}
+
+	content, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
+	if !ok {
+		return "", fmt.Errorf("llm/gemini: unexpected response format from Gemini AI API")
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,50 @@
+// Package llm provides a pluggable interface for multimodal document-parsing
+// backends (Gemini, OpenAI/Azure OpenAI chat completions with vision, and a
+// self-hosted Ollama endpoint), selected at startup via environment
+// variables rather than compiled in.
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Parser extracts structured data from a document by sending its bytes and a
+// prompt describing the fields to extract to a backing multimodal model, and
+// returns the model's raw text response. Callers are responsible for
+// interpreting/unmarshalling the returned text.
+type Parser interface {
+	// Parse sends prompt and the file's bytes to the backend and returns its
+	// raw text response.
+	Parse(ctx context.Context, prompt string, file []byte, mimeType string) (string, error)
+	// Name identifies the backend, e.g. "gemini", "openai", "ollama".
+	Name() string
+}
+
+// Config selects and configures a Parser backend. It is built from the
+// LLM_PROVIDER, LLM_MODEL, LLM_BASE_URL, GEMINI_API_KEY, and OPENAI_API_KEY
+// environment variables by main().
+type Config struct {
+	// Provider selects the backend: "gemini" (default), "openai",
+	// "azure-openai", or "ollama".
+	Provider string
+	APIKey   string
+	Model    string
+	// BaseURL is required for "ollama" and overrides the default endpoint
+	// for "openai"/"azure-openai".
+	BaseURL string
+}
+
+// New builds the Parser selected by cfg.Provider.
+func New(cfg Config) (Parser, error) {
+	switch cfg.Provider {
+	case "", "gemini":
+		return newGeminiParser(cfg)
+	case "openai", "azure-openai":
+		return newOpenAIParser(cfg)
+	case "ollama":
+		return newOllamaParser(cfg)
+	default:
+		return nil, fmt.Errorf("llm: unknown provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ollamaParser implements Parser against a self-hosted Ollama server's chat
+// API (POST {baseURL}/api/chat with base64-encoded images), letting users
+// run entirely local models instead of calling out to a hosted provider.
+type ollamaParser struct {
+	model   string
+	baseURL string
+}
+
+func newOllamaParser(cfg Config) (Parser, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("llm/ollama: base URL is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "llava"
+	}
+
+	return &ollamaParser{model: model, baseURL: cfg.BaseURL}, nil
+}
+
+func (p *ollamaParser) Name() string {
+	return "ollama"
+}
+
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []ollamaChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+}
+
+type ollamaChatMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Error string `json:"error,omitempty"`
+}
+
+func (p *ollamaParser) Parse(ctx context.Context, prompt string, file []byte, mimeType string) (string, error) {
+	reqBody := ollamaChatRequest{
+		Model: p.model,
+		Messages: []ollamaChatMessage{
+			{Role: "user", Content: prompt, Images: []string{base64.StdEncoding.EncodeToString(file)}},
+		},
+		Stream: false,
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("llm/ollama: error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("llm/ollama: error building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("llm/ollama: error calling chat API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("llm/ollama: error reading response: %w", err)
+	}
+
+	var parsed ollamaChatResponse
+	decodeErr := json.Unmarshal(respBody, &parsed)
+
+	if resp.StatusCode != http.StatusOK {
+		message := string(respBody)
+		if decodeErr == nil && parsed.Error != "" {
+			message = parsed.Error
+		}
+		return "", &apiError{statusCode: resp.StatusCode, message: message}
+	}
+
+	if decodeErr != nil {
+		return "", fmt.Errorf("llm/ollama: error decoding response: %w", decodeErr)
+	}
+
+	return parsed.Message.Content, nil
+}
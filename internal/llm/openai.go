@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// openAIParser implements Parser on top of the OpenAI (and Azure OpenAI,
+// which speaks the same wire format) chat completions API with vision
+// input. The file is embedded as a base64 data URI image_url content part,
+// so only image MIME types are usable with this backend.
+type openAIParser struct {
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+func newOpenAIParser(cfg Config) (Parser, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("llm/openai: API key is required")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	return &openAIParser{apiKey: cfg.APIKey, model: model, baseURL: baseURL}, nil
+}
+
+func (p *openAIParser) Name() string {
+	return "openai"
+}
+
+type openAIChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+}
+
+type openAIChatMessage struct {
+	Role    string              `json:"role"`
+	Content []openAIChatContent `json:"content"`
+}
+
+type openAIChatContent struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *openAIParser) Parse(ctx context.Context, prompt string, file []byte, mimeType string) (string, error) {
+	dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(file))
+
+	reqBody := openAIChatRequest{
+		Model: p.model,
+		Messages: []openAIChatMessage{
+			{
+				Role: "user",
+				Content: []openAIChatContent{
+					{Type: "text", Text: prompt},
+					{Type: "image_url", ImageURL: &openAIImageURL{URL: dataURI}},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("llm/openai: error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("llm/openai: error building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("llm/openai: error calling chat completions API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("llm/openai: error reading response: %w", err)
+	}
+
+	var parsed openAIChatResponse
+	decodeErr := json.Unmarshal(respBody, &parsed)
+
+	if resp.StatusCode != http.StatusOK {
+		message := string(respBody)
+		if decodeErr == nil && parsed.Error != nil {
+			message = parsed.Error.Message
+		}
+		return "", &apiError{statusCode: resp.StatusCode, message: message}
+	}
+
+	if decodeErr != nil {
+		return "", fmt.Errorf("llm/openai: error decoding response: %w", decodeErr)
+	}
+
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("llm/openai: no choices in response")
+	}
+
+	return parsed.Choices[0].Message.Content, nil
+}
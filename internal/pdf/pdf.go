@@ -0,0 +1,57 @@
+// Package pdf splits a PDF document into page-range chunks so large files
+// can be parsed a few pages at a time instead of in one shot, and reports
+// the page counts/ranges that callers use to request per-field source pages
+// from the LLM.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// PageRange is an inclusive, 1-based page range within a document.
+type PageRange struct {
+	Start int
+	End   int
+}
+
+// PageRanges splits a totalPages-page document into consecutive, inclusive
+// page ranges of at most pagesPerChunk pages each. A pagesPerChunk less
+// than 1 is treated as "no chunking" and yields a single range covering
+// the whole document.
+func PageRanges(totalPages, pagesPerChunk int) []PageRange {
+	if pagesPerChunk < 1 {
+		pagesPerChunk = totalPages
+	}
+
+	var ranges []PageRange
+	for start := 1; start <= totalPages; start += pagesPerChunk {
+		end := start + pagesPerChunk - 1
+		if end > totalPages {
+			end = totalPages
+		}
+		ranges = append(ranges, PageRange{Start: start, End: end})
+	}
+	return ranges
+}
+
+// PageCount returns the number of pages in the PDF data.
+func PageCount(data []byte) (int, error) {
+	n, err := api.PageCount(bytes.NewReader(data), nil)
+	if err != nil {
+		return 0, fmt.Errorf("pdf: error counting pages: %w", err)
+	}
+	return n, nil
+}
+
+// Extract returns a new, standalone PDF containing only r's pages of data.
+func Extract(data []byte, r PageRange) ([]byte, error) {
+	var buf bytes.Buffer
+	selector := []string{fmt.Sprintf("%d-%d", r.Start, r.End)}
+	if err := api.Trim(bytes.NewReader(data), &buf, selector, nil); err != nil {
+		return nil, fmt.Errorf("pdf: error extracting pages %d-%d: %w", r.Start, r.End, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,30 @@
+package pdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPageRanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		totalPages    int
+		pagesPerChunk int
+		want          []PageRange
+	}{
+		{"evenly divides", 8, 4, []PageRange{{1, 4}, {5, 8}}},
+		{"remainder in last chunk", 10, 4, []PageRange{{1, 4}, {5, 8}, {9, 10}}},
+		{"single page document", 1, 4, []PageRange{{1, 1}}},
+		{"fewer pages than chunk size", 3, 4, []PageRange{{1, 3}}},
+		{"no chunking means one range", 10, 0, []PageRange{{1, 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PageRanges(tt.totalPages, tt.pagesPerChunk)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PageRanges(%d, %d) = %v, want %v", tt.totalPages, tt.pagesPerChunk, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package validators implements Form 941-specific post-processing that
+// doctype.Type.Validate's generic per-field type/pattern checks can't
+// express: normalizing currency strings and EINs (including common OCR
+// mistakes), and checking the form's cross-box arithmetic invariants.
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var einPattern = regexp.MustCompile(`^\d{2}-?\d{7}$`)
+
+// NormalizeEIN strips any formatting from s and returns its bare 9 digits,
+// accepting both "123456789" and "12-3456789". ok is false if s isn't a
+// validly-formatted EIN.
+func NormalizeEIN(s string) (normalized string, ok bool) {
+	s = strings.TrimSpace(s)
+	if !einPattern.MatchString(s) {
+		return "", false
+	}
+	return strings.ReplaceAll(s, "-", ""), true
+}
+
+// CurrencyCents normalizes a "$11.11"-formatted string to its integer cent
+// value, auto-correcting the common OCR mistake of an "O" read in place of
+// a "0". There's no decimal library available in this tree, so currency is
+// represented as whole cents (an int64) rather than a true decimal type.
+func CurrencyCents(s string) (int64, error) {
+	orig := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "O", "0")
+	s = strings.TrimPrefix(s, "$")
+
+	dollarsStr, centsStr, hasCents := strings.Cut(s, ".")
+	dollars, err := strconv.ParseInt(dollarsStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("validators: invalid currency value %q", orig)
+	}
+
+	var cents int64
+	if hasCents {
+		if len(centsStr) == 1 {
+			centsStr += "0"
+		}
+		if len(centsStr) != 2 {
+			return 0, fmt.Errorf("validators: invalid currency value %q", orig)
+		}
+		if cents, err = strconv.ParseInt(centsStr, 10, 64); err != nil {
+			return 0, fmt.Errorf("validators: invalid currency value %q", orig)
+		}
+	}
+
+	return dollars*100 + cents, nil
+}
+
+// FormatCents renders a whole-cent amount in the form's canonical "$11.11"
+// format.
+func FormatCents(cents int64) string {
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
+
+// ValidateForm941Arithmetic checks Form 941's known cross-box arithmetic
+// invariants against fields (a parsed, flat field-name-to-value map, as
+// passed to doctype.Type.Validate) and returns one message per violated
+// invariant:
+//
+//   - Box 5e = Box 5a + Box 5b + Box 5c + Box 5d, column 2
+//   - Box 6 = Box 3 + Box 5e
+//   - Box 10 = Box 6 − Box 7 − Box 8 − Box 9
+//
+// An invariant is skipped, not reported, if any box it depends on is
+// missing or isn't a parseable currency value — doctype.Validate's own
+// pattern check already flags a malformed value.
+func ValidateForm941Arithmetic(fields map[string]interface{}) []string {
+	box := func(name string) (int64, bool) {
+		s, ok := fields[name].(string)
+		if !ok {
+			return 0, false
+		}
+		cents, err := CurrencyCents(s)
+		if err != nil {
+			return 0, false
+		}
+		return cents, true
+	}
+
+	var errs []string
+
+	box5a, ok5a := box("Box 5a Column 2")
+	box5b, ok5b := box("Box 5b Column 2")
+	box5c, ok5c := box("Box 5c Column 2")
+	box5d, ok5d := box("Box 5d Column 2")
+	box5e, ok5e := box("Box 5e")
+	if ok5a && ok5b && ok5c && ok5d && ok5e {
+		if sum := box5a + box5b + box5c + box5d; sum != box5e {
+			errs = append(errs, fmt.Sprintf("Box 5e (%s) does not equal the sum of Box 5a-5d column 2 (%s)", FormatCents(box5e), FormatCents(sum)))
+		}
+	}
+
+	box3, ok3 := box("Box 3")
+	box6, ok6 := box("Box 6")
+	box6Mismatch := false
+	if ok3 && ok5e && ok6 {
+		if want := box3 + box5e; want != box6 {
+			errs = append(errs, fmt.Sprintf("Box 6 (%s) does not equal Box 3 + Box 5e (%s)", FormatCents(box6), FormatCents(want)))
+			box6Mismatch = true
+		}
+	}
+
+	box7, ok7 := box("Box 7")
+	box8, ok8 := box("Box 8")
+	box9, ok9 := box("Box 9")
+	box10, ok10 := box("Box 10")
+	// Skip this invariant once the Box 6 check above has already flagged
+	// Box 6 as inconsistent, rather than comparing Box 10 against a Box 6
+	// we know is wrong and cascading into a second, spurious error.
+	if ok6 && ok7 && ok8 && ok9 && ok10 && !box6Mismatch {
+		if want := box6 - box7 - box8 - box9; want != box10 {
+			errs = append(errs, fmt.Sprintf("Box 10 (%s) does not equal Box 6 - Box 7 - Box 8 - Box 9 (%s)", FormatCents(box10), FormatCents(want)))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,114 @@
+package validators
+
+import "testing"
+
+func TestNormalizeEIN(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"bare digits", "123456789", "123456789", true},
+		{"formatted", "12-3456789", "123456789", true},
+		{"too short", "12345", "", false},
+		{"non-digits", "AB-CDEFGHI", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeEIN(tt.in)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("NormalizeEIN(%q) = (%q, %v), want (%q, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCurrencyCents(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"plain", "$11.11", 1111, false},
+		{"ocr zero swap", "$1O.OO", 1000, false},
+		{"single digit cents", "$5.5", 550, false},
+		{"no cents", "$5", 500, false},
+		{"not currency", "not money", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CurrencyCents(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CurrencyCents(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("CurrencyCents(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCents(t *testing.T) {
+	if got := FormatCents(1111); got != "$11.11" {
+		t.Errorf("FormatCents(1111) = %q, want %q", got, "$11.11")
+	}
+	if got := FormatCents(500); got != "$5.00" {
+		t.Errorf("FormatCents(500) = %q, want %q", got, "$5.00")
+	}
+}
+
+func validForm941Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"Box 5a Column 2": "$10.00",
+		"Box 5b Column 2": "$20.00",
+		"Box 5c Column 2": "$30.00",
+		"Box 5d Column 2": "$40.00",
+		"Box 5e":          "$100.00",
+		"Box 3":           "$50.00",
+		"Box 6":           "$150.00",
+		"Box 7":           "$5.00",
+		"Box 8":           "$5.00",
+		"Box 9":           "$0.00",
+		"Box 10":          "$140.00",
+	}
+}
+
+func TestValidateForm941Arithmetic(t *testing.T) {
+	if errs := ValidateForm941Arithmetic(validForm941Fields()); len(errs) != 0 {
+		t.Errorf("ValidateForm941Arithmetic(valid) = %v, want no errors", errs)
+	}
+
+	t.Run("box 5e mismatch", func(t *testing.T) {
+		fields := validForm941Fields()
+		fields["Box 5e"] = "$999.00"
+		if errs := ValidateForm941Arithmetic(fields); len(errs) != 2 {
+			t.Errorf("ValidateForm941Arithmetic() = %v, want 2 errors (5e sum, and the box 6 check that depends on it)", errs)
+		}
+	})
+
+	t.Run("box 6 mismatch", func(t *testing.T) {
+		fields := validForm941Fields()
+		fields["Box 6"] = "$999.00"
+		if errs := ValidateForm941Arithmetic(fields); len(errs) != 1 {
+			t.Errorf("ValidateForm941Arithmetic() = %v, want 1 error", errs)
+		}
+	})
+
+	t.Run("box 10 mismatch", func(t *testing.T) {
+		fields := validForm941Fields()
+		fields["Box 10"] = "$999.00"
+		if errs := ValidateForm941Arithmetic(fields); len(errs) != 1 {
+			t.Errorf("ValidateForm941Arithmetic() = %v, want 1 error", errs)
+		}
+	})
+
+	t.Run("missing boxes skip rather than fail", func(t *testing.T) {
+		if errs := ValidateForm941Arithmetic(map[string]interface{}{}); len(errs) != 0 {
+			t.Errorf("ValidateForm941Arithmetic({}) = %v, want no errors", errs)
+		}
+	})
+}
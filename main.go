@@ -2,51 +2,49 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"github.com/google/generative-ai-go/genai"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq" // PostgreSQL driver
-	"google.golang.org/api/option"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"DocumentParsingSystem/internal/doctype"
+	"DocumentParsingSystem/internal/httpx"
+	"DocumentParsingSystem/internal/llm"
+	"DocumentParsingSystem/internal/pdf"
+	"DocumentParsingSystem/internal/validators"
 )
 
-// JobDetails represents the structured response format for parsed job information
-type JobDetails struct {
-	Title          string `json:"title"`
-	Salary         string `json:"salary"`
-	Location       string `json:"location"`
-	Experience     string `json:"experience"`
-	EmploymentType string `json:"employment-type"`
-}
+// defaultPagesPerChunk is how many pages of a PDF are sent to the LLM in a
+// single call when PAGES_PER_CHUNK isn't set.
+const defaultPagesPerChunk = 4
 
-type Form941 struct {
-	EIN       string `json:"EIN"`
-	Name      string `json:"Name"`
-	TradeName string `json:"Trade Name"`
-	Address   string `json:"Address"`
-	Box1      string `json:"Box 1"`
-	Box2      string `json:"Box 2"`
-	Box3      string `json:"Box 3"`
-	Box4      bool   `json:"Box 4"`
-	Box5e     string `json:"Box 5e"`
-	Box5f     string `json:"Box 5f"`
-	Box6      string `json:"Box 6"`
-	Box7      string `json:"Box 7"`
-	Box8      string `json:"Box 8"`
-	Box9      string `json:"Box 9"`
-	Box10     string `json:"Box 10"`
-	Box11     string `json:"Box 11"`
-	Box12     string `json:"Box 12"`
-	Box13     string `json:"Box 13"`
-	Box14     string `json:"Box 14"`
-}
+// maxChunkWorkers bounds how many page-range chunks are parsed concurrently
+// against the LLM provider, so a large PDF doesn't open one request per
+// chunk all at once.
+const maxChunkWorkers = 4
+
+// defaultDocumentWorkers is how many background goroutines process queued
+// /documents uploads when DOCUMENT_WORKERS isn't set.
+const defaultDocumentWorkers = 2
+
+// documentJobQueueSize bounds how many uploaded documents can be waiting
+// for a worker before POST /documents starts blocking.
+const documentJobQueueSize = 100
+
+// defaultParseRateLimit is how many /parse-document requests a single
+// client IP may make per minute when RATE_LIMIT_PER_MINUTE isn't set.
+const defaultParseRateLimit = 30
 
 // ParsedFields represents the data to be stored in the database
 type ParsedFields struct {
@@ -64,54 +62,59 @@ type FinalizeRequest struct {
 	DocumentType string          `json:"document_type"`
 }
 
-func jobDetailsPrompt() string {
-	return `You are a document parser specialized in extracting job information.
-Extract the following details from the document: job title, salary, location, experience required, and employment type.
-
-Return ONLY a valid JSON object with the following structure:
-{
-  "title": "Job Title",
-  "salary": "Salary Information",
-  "location": "Job Location",
-  "experience": "Required Experience",
-  "employment-type": "Type of Employment (Full-time, Part-time, etc.)"
-}
-
-Do not include any explanations, markdown formatting, or additional text outside the JSON object.
-If you cannot find a specific field, use an empty string for that field.`
-}
-
-func form941Prompt() string {
-	return `You are a document parser specialized in extracting job-related information.
-Extract the following details from the document based on Form 941: EIN, name, trade name, address, and boxes 1–14.
-Note that EIN values are consistently formatted as separate digits that, when combined, form a 9-digit number.
-All box fields except for Box 4 should follow this format: $11.11 — consisting of a dollar sign, one or more digits, a decimal point, and two digits.
-
-Return ONLY a valid JSON object with the following structure:
-{
-	"EIN": "123456789",
-	"Name": "Company Name",
-	"Trade name": "Trade name",
-	"Address": "Full address",
-	"Box 1": "$11.11",
-	"Box 2": "$22.22",
-	"Box 3": "$33.33",
-	"Box 4": true or false,
-	"Box 5e": "$55.55",
-	"Box 5f": "$55.55",
-	"Box 6": "$66.66",
-	"Box 7": "$77.77",
-	"Box 8": "$88.88",
-	"Box 9": "$99.99"
-	"Box 10": "$100.00",
-	"Box 11": "$111.11",
-	"Box 12": "$121.21"
-	"Box 13": "$121.21"
-	"Box 14": "$121.21"
-}
+// registerBuiltinDocumentTypes seeds the doctype registry with the two
+// document types main.go used to hardcode as JobDetails/Form941 structs, so
+// /parse-document keeps accepting the same document types (with form_941
+// still the default).
+// Additional types can be added at runtime via POST /document-types without
+// a restart.
+func registerBuiltinDocumentTypes() error {
+	if err := doctype.Register(doctype.Type{
+		Name: "job_details",
+		Fields: []doctype.Field{
+			{Name: "title", Type: doctype.FieldTypeString, Required: true},
+			{Name: "salary", Type: doctype.FieldTypeString},
+			{Name: "location", Type: doctype.FieldTypeString},
+			{Name: "experience", Type: doctype.FieldTypeString},
+			{Name: "employment-type", Type: doctype.FieldTypeString},
+		},
+	}); err != nil {
+		return err
+	}
 
-Do not include any explanations, markdown formatting, or additional text outside the JSON object.
-If you cannot find a specific field, use an empty string for that field.`
+	moneyPattern := `^\$\d+\.\d{2}$`
+	return doctype.Register(doctype.Type{
+		Name: "form_941",
+		Description: "You are a document parser specialized in extracting job-related information.\n" +
+			"Extract the following details from the document based on Form 941: EIN, name, trade name, address, and boxes 1–14, including the column 2 tax amounts for boxes 5a–5d.\n" +
+			"Note that EIN values are consistently formatted as separate digits that, when combined, form a 9-digit number.\n" +
+			"All box fields except for Box 4 should follow this format: $11.11 — consisting of a dollar sign, one or more digits, a decimal point, and two digits.",
+		Fields: []doctype.Field{
+			{Name: "EIN", Type: doctype.FieldTypeString, Required: true, Pattern: `^\d{9}$`},
+			{Name: "Name", Type: doctype.FieldTypeString},
+			{Name: "Trade Name", Type: doctype.FieldTypeString},
+			{Name: "Address", Type: doctype.FieldTypeString},
+			{Name: "Box 1", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 2", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 3", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 4", Type: doctype.FieldTypeBoolean},
+			{Name: "Box 5a Column 2", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 5b Column 2", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 5c Column 2", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 5d Column 2", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 5e", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 5f", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 6", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 7", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 8", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 9", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 10", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 11", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 12", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 13", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+			{Name: "Box 14", Type: doctype.FieldTypeString, Pattern: moneyPattern},
+		},
+	})
 }
 
 // cleanJSONResponse removes markdown code block markers from a JSON string
@@ -193,85 +196,407 @@ func initDB(db *sql.DB) error {
 		return fmt.Errorf("error creating parsed_fields table: %w", err)
 	}
 
+	// Create the documents table if it doesn't exist. It's keyed by the
+	// SHA-256 of the uploaded file plus the document type it was parsed as,
+	// so re-uploading the same file doesn't re-spend against the LLM API.
+	createDocumentsTableSQL := `
+	CREATE TABLE IF NOT EXISTS documents (
+		id SERIAL PRIMARY KEY,
+		sha256 VARCHAR NOT NULL,
+		document_name VARCHAR NOT NULL,
+		document_type VARCHAR NOT NULL,
+		status VARCHAR NOT NULL DEFAULT 'queued',
+		parsed_result JSONB,
+		overall_confidence DOUBLE PRECISION,
+		validation_errors JSONB,
+		error_message TEXT,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (sha256, document_type)
+	);`
+
+	if _, err := db.Exec(createDocumentsTableSQL); err != nil {
+		return fmt.Errorf("error creating documents table: %w", err)
+	}
+
 	log.Println("Database initialized successfully")
 	return nil
 }
 
-// DocumentType represents the type of document to parse
-type DocumentType string
+// fieldResult is one extracted field's value together with the LLM's
+// confidence in it and the absolute page it was found on. It's what each
+// field resolves to in /parse-document's parsed_result, replacing the bare
+// values doctype.Type.Prompt used to ask for.
+type fieldResult struct {
+	Value      interface{} `json:"value"`
+	Confidence float64     `json:"confidence"`
+	SourcePage int         `json:"source_page,omitempty"`
+}
+
+// mimeTypeForFilename guesses a document's MIME type from its extension,
+// falling back to application/octet-stream for anything unrecognized.
+func mimeTypeForFilename(filename string) string {
+	switch {
+	case strings.HasSuffix(strings.ToLower(filename), ".pdf"):
+		return "application/pdf"
+	case strings.HasSuffix(strings.ToLower(filename), ".csv"):
+		return "text/csv"
+	case strings.HasSuffix(strings.ToLower(filename), ".png"):
+		return "image/png"
+	case strings.HasSuffix(strings.ToLower(filename), ".txt"):
+		return "text/plain"
+	case strings.HasSuffix(strings.ToLower(filename), ".doc"):
+		return "application/msword"
+	case strings.HasSuffix(strings.ToLower(filename), ".docx"):
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// isEmptyFieldValue reports whether v is the "not found" placeholder value
+// the prompt asks the model to use (an empty/whitespace string, or nil).
+// Booleans are never treated as empty: false is a legitimate answer.
+func isEmptyFieldValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(val) == ""
+	default:
+		return false
+	}
+}
+
+// getEnvInt reads an integer environment variable, falling back to def if
+// it's unset or not a valid integer.
+func getEnvInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Warning: %s=%q is not a valid integer, using default %d", name, v, def)
+		return def
+	}
+	return n
+}
+
+// parseChunk sends one page range's bytes to parser using dt's generated
+// prompt, retrying transient failures with backoff, then unmarshals the
+// (markdown-cleaned) response into a per-field fieldResult map.
+func parseChunk(ctx context.Context, parser llm.Parser, chunk []byte, mimeType string, dt doctype.Type, startPage, endPage, totalPages int) (map[string]fieldResult, error) {
+	rawResponse, err := llm.WithRetry(ctx, func() (string, error) {
+		return parser.Parse(ctx, dt.Prompt(startPage, endPage, totalPages), chunk, mimeType)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error calling %s provider: %w", parser.Name(), err)
+	}
+
+	jsonStr := cleanJSONResponse(rawResponse)
+	log.Printf("Cleaned JSON response for pages %d-%d: %s", startPage, endPage, jsonStr)
+
+	var fields map[string]fieldResult
+	if err := json.Unmarshal([]byte(jsonStr), &fields); err != nil {
+		return nil, fmt.Errorf("error parsing %s response for document type %q: %w\nResponse: %s", parser.Name(), dt.Name, err, jsonStr)
+	}
+
+	return fields, nil
+}
+
+// parseDocumentFields splits fileContent into PAGES_PER_CHUNK-page groups
+// (the whole file in one group for non-PDF mime types, or if it has no more
+// pages than that), parses each group against the LLM through a bounded
+// worker pool, and merges the groups' results by taking the
+// highest-confidence non-empty value per field. It returns the merged
+// fields, an overall confidence (the average of the merged fields'
+// confidences), and dt's validation errors against the merged values.
+func parseDocumentFields(ctx context.Context, parser llm.Parser, fileContent []byte, mimeType string, dt doctype.Type) (map[string]fieldResult, float64, []string, error) {
+	totalPages := 1
+	chunks := [][]byte{fileContent}
+	ranges := []pdf.PageRange{{Start: 1, End: 1}}
+
+	if mimeType == "application/pdf" {
+		if n, err := pdf.PageCount(fileContent); err != nil {
+			log.Printf("Warning: failed to count PDF pages, parsing as a single chunk: %v", err)
+		} else {
+			totalPages = n
+			ranges = pdf.PageRanges(totalPages, getEnvInt("PAGES_PER_CHUNK", defaultPagesPerChunk))
+			chunks = make([][]byte, len(ranges))
+			for i, r := range ranges {
+				data, err := pdf.Extract(fileContent, r)
+				if err != nil {
+					return nil, 0, nil, fmt.Errorf("error extracting pages %d-%d: %w", r.Start, r.End, err)
+				}
+				chunks[i] = data
+			}
+		}
+	}
+
+	type chunkOutcome struct {
+		fields map[string]fieldResult
+		err    error
+	}
+	outcomes := make([]chunkOutcome, len(chunks))
+
+	sem := make(chan struct{}, maxChunkWorkers)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []byte, r pdf.PageRange) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fields, err := parseChunk(ctx, parser, chunk, mimeType, dt, r.Start, r.End, totalPages)
+			outcomes[i] = chunkOutcome{fields: fields, err: err}
+		}(i, chunk, ranges[i])
+	}
+	wg.Wait()
+
+	merged := map[string]fieldResult{}
+	var lastErr error
+	succeeded := 0
+	for i, o := range outcomes {
+		if o.err != nil {
+			log.Printf("Warning: chunk %d (pages %d-%d) failed to parse: %v", i, ranges[i].Start, ranges[i].End, o.err)
+			lastErr = o.err
+			continue
+		}
+		succeeded++
+		for name, fr := range o.fields {
+			if isEmptyFieldValue(fr.Value) {
+				continue
+			}
+			if existing, ok := merged[name]; !ok || fr.Confidence > existing.Confidence {
+				merged[name] = fr
+			}
+		}
+	}
+	if succeeded == 0 {
+		return nil, 0, nil, fmt.Errorf("all %d chunk(s) failed to parse: %w", len(chunks), lastErr)
+	}
+
+	values := make(map[string]interface{}, len(merged))
+	for name, fr := range merged {
+		values[name] = fr.Value
+	}
+	normalizeFieldValues(dt, merged, values)
+
+	var confidenceSum float64
+	for _, fr := range merged {
+		confidenceSum += fr.Confidence
+	}
+	overallConfidence := 0.0
+	if len(merged) > 0 {
+		overallConfidence = confidenceSum / float64(len(merged))
+	}
+
+	validationErrors := dt.Validate(values)
+	if dt.Name == "form_941" {
+		validationErrors = append(validationErrors, validators.ValidateForm941Arithmetic(values)...)
+	}
+
+	return merged, overallConfidence, validationErrors, nil
+}
+
+// normalizeFieldValues auto-corrects common OCR mistakes in the merged
+// field values before validation: the EIN field has stray formatting
+// stripped, and any field whose declared pattern is a money pattern has an
+// OCR'd "O"/"0" swap corrected and is re-rendered in the canonical $11.11
+// form. merged is updated in place alongside values so callers returning
+// merged as parsed_result see the corrected value too.
+func normalizeFieldValues(dt doctype.Type, merged map[string]fieldResult, values map[string]interface{}) {
+	for _, f := range dt.Fields {
+		s, ok := values[f.Name].(string)
+		if !ok {
+			continue
+		}
+
+		var corrected string
+		switch {
+		case f.Name == "EIN":
+			norm, ok := validators.NormalizeEIN(s)
+			if !ok {
+				continue
+			}
+			corrected = norm
+		case strings.Contains(f.Pattern, `\$`):
+			cents, err := validators.CurrencyCents(s)
+			if err != nil {
+				continue
+			}
+			corrected = validators.FormatCents(cents)
+		default:
+			continue
+		}
+
+		values[f.Name] = corrected
+		if fr, ok := merged[f.Name]; ok {
+			fr.Value = corrected
+			merged[f.Name] = fr
+		}
+	}
+}
 
+// Document status values for the async POST/GET /documents pipeline.
 const (
-	JobDetailsType DocumentType = "job_details"
-	Form941Type    DocumentType = "form_941"
+	documentStatusQueued     = "queued"
+	documentStatusProcessing = "processing"
+	documentStatusCompleted  = "completed"
+	documentStatusFailed     = "failed"
 )
 
-// parseDocumentWithGeminiMultimodal uses the Gemini AI API to extract structured data from a document
-// by sending the file directly as binary data instead of as text
-func parseDocumentWithGeminiMultimodal[T JobDetails | Form941](ctx context.Context, client *genai.Client, fileContent []byte, mimeType string, docType DocumentType) (T, error) {
-	// Determine which prompt to use based on document type
-	var schemaInstruction string
-	switch docType {
-	case JobDetailsType:
-		schemaInstruction = jobDetailsPrompt()
-	case Form941Type:
-		schemaInstruction = form941Prompt()
-	default:
-		var zero T
-		return zero, fmt.Errorf("unsupported document type: %s", docType)
+// documentColumns is the column list shared by every query that scans a
+// documents row into a documentRecord, so the two stay in sync.
+const documentColumns = "id, sha256, document_name, document_type, status, parsed_result, overall_confidence, validation_errors, error_message, created_at, updated_at"
+
+// documentRecord mirrors a row of the documents table.
+type documentRecord struct {
+	ID                int             `json:"id"`
+	SHA256            string          `json:"sha256"`
+	DocumentName      string          `json:"document_name"`
+	DocumentType      string          `json:"document_type"`
+	Status            string          `json:"status"`
+	ParsedResult      json.RawMessage `json:"parsed_result,omitempty"`
+	OverallConfidence *float64        `json:"overall_confidence,omitempty"`
+	ValidationErrors  json.RawMessage `json:"validation_errors,omitempty"`
+	ErrorMessage      string          `json:"error_message,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+// documentRowScanner is satisfied by both *sql.Row and *sql.Rows, so
+// scanDocumentRow works for either a single QueryRow or a Query loop.
+type documentRowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDocumentRow scans a row selected with documentColumns into a
+// documentRecord, translating the nullable columns into their pointer/
+// RawMessage equivalents.
+func scanDocumentRow(row documentRowScanner) (documentRecord, error) {
+	var rec documentRecord
+	var parsedResult, validationErrors []byte
+	var overallConfidence sql.NullFloat64
+	var errorMessage sql.NullString
+
+	err := row.Scan(&rec.ID, &rec.SHA256, &rec.DocumentName, &rec.DocumentType, &rec.Status,
+		&parsedResult, &overallConfidence, &validationErrors, &errorMessage, &rec.CreatedAt, &rec.UpdatedAt)
+	if err != nil {
+		return documentRecord{}, err
+	}
+
+	rec.ParsedResult = parsedResult
+	rec.ValidationErrors = validationErrors
+	if overallConfidence.Valid {
+		rec.OverallConfidence = &overallConfidence.Float64
 	}
+	rec.ErrorMessage = errorMessage.String
+	return rec, nil
+}
 
-	// Create the chat completion request
-	model := client.GenerativeModel("gemini-2.0-flash")
-	model.SetTemperature(0.0) // Set to 0 for more deterministic responses
+// documentJob is one unit of work for the background parse workers: an
+// already-persisted document's file bytes and the type to parse it as.
+type documentJob struct {
+	id           int
+	fileContent  []byte
+	mimeType     string
+	documentType string
+}
 
-	// Create the prompt with schema instructions and the file as binary data
-	prompt := []genai.Part{
-		genai.Text(schemaInstruction),
-		// Send the file directly as binary data with its MIME type
-		genai.Blob{
-			MIMEType: mimeType,
-			Data:     fileContent,
-		},
+// findOrCreateDocument looks up a document already parsed (or in flight)
+// for this exact file content and document type, so repeated uploads of
+// the same file don't re-spend against the LLM API. If none exists yet it
+// inserts a new queued row. The second return value reports whether an
+// existing row was found rather than a new one created.
+func findOrCreateDocument(db *sql.DB, sha256Hash, documentName, documentType string) (documentRecord, bool, error) {
+	now := time.Now()
+	rec, err := scanDocumentRow(db.QueryRow(`
+		INSERT INTO documents (sha256, document_name, document_type, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (sha256, document_type) DO NOTHING
+		RETURNING `+documentColumns,
+		sha256Hash, documentName, documentType, documentStatusQueued, now))
+	if err == nil {
+		return rec, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return documentRecord{}, false, fmt.Errorf("error creating document: %w", err)
 	}
 
-	// Call the Gemini AI API
-	resp, err := model.GenerateContent(ctx, prompt...)
+	// The INSERT hit the UNIQUE(sha256, document_type) constraint, so this
+	// file/type has already been queued, parsed, or failed before.
+	rec, err = scanDocumentRow(db.QueryRow(`SELECT `+documentColumns+` FROM documents WHERE sha256 = $1 AND document_type = $2`,
+		sha256Hash, documentType))
 	if err != nil {
-		var zero T
-		return zero, fmt.Errorf("error calling Gemini AI API: %w", err)
+		return documentRecord{}, false, fmt.Errorf("error looking up existing document: %w", err)
 	}
+	return rec, true, nil
+}
 
-	// Extract the content from the response
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		var zero T
-		return zero, fmt.Errorf("no response from Gemini AI API")
+// startDocumentWorkers launches workerCount background goroutines that pull
+// queued document jobs off jobs and parse them against parser, persisting
+// the result so GET /documents/{id} can be polled for status.
+func startDocumentWorkers(db *sql.DB, parser llm.Parser, jobs <-chan documentJob, workerCount int) {
+	for i := 0; i < workerCount; i++ {
+		go func() {
+			for job := range jobs {
+				processDocumentJob(db, parser, job)
+			}
+		}()
 	}
+}
 
-	// Get the text content from the response
-	content, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
-	if !ok {
-		var zero T
-		return zero, fmt.Errorf("unexpected response format from Gemini AI API")
+// failDocument marks a document's row as failed with message, so a poller
+// of GET /documents/{id} can surface why parsing didn't complete.
+func failDocument(db *sql.DB, id int, message string) {
+	if _, err := db.Exec(`UPDATE documents SET status = $1, error_message = $2, updated_at = $3 WHERE id = $4`,
+		documentStatusFailed, message, time.Now(), id); err != nil {
+		log.Printf("Warning: failed to mark document %d as failed: %v", id, err)
 	}
+}
 
-	// Clean the response to ensure it's valid JSON
-	jsonStr := string(content)
+// processDocumentJob parses one queued document and persists its result,
+// moving it through processing -> completed, or -> failed on error.
+func processDocumentJob(db *sql.DB, parser llm.Parser, job documentJob) {
+	if _, err := db.Exec(`UPDATE documents SET status = $1, updated_at = $2 WHERE id = $3`,
+		documentStatusProcessing, time.Now(), job.id); err != nil {
+		log.Printf("Warning: failed to mark document %d as processing: %v", job.id, err)
+	}
 
-	// Remove markdown code block markers if present
-	jsonStr = cleanJSONResponse(jsonStr)
+	dt, ok := doctype.Get(job.documentType)
+	if !ok {
+		failDocument(db, job.id, fmt.Sprintf("unsupported document type %q", job.documentType))
+		return
+	}
 
-	// Log the cleaned JSON for debugging
-	log.Printf("Cleaned JSON response: %s", jsonStr)
+	parsedResult, overallConfidence, validationErrors, err := parseDocumentFields(context.Background(), parser, job.fileContent, job.mimeType, dt)
+	if err != nil {
+		failDocument(db, job.id, err.Error())
+		return
+	}
 
-	// Parse the JSON response into the appropriate struct based on document type
-	var result T
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		log.Printf("JSON parsing failed for %T: %v", result, err)
-		var zero T
-		return zero, fmt.Errorf("error parsing Gemini AI response for %T: %w\nResponse: %s", result, err, jsonStr)
+	parsedJSON, err := json.Marshal(parsedResult)
+	if err != nil {
+		failDocument(db, job.id, fmt.Sprintf("error encoding parsed result: %v", err))
+		return
+	}
+	var validationJSON []byte
+	if len(validationErrors) > 0 {
+		if validationJSON, err = json.Marshal(validationErrors); err != nil {
+			failDocument(db, job.id, fmt.Sprintf("error encoding validation errors: %v", err))
+			return
+		}
 	}
 
-	return result, nil
+	if _, err := db.Exec(`
+		UPDATE documents SET status = $1, parsed_result = $2, overall_confidence = $3, validation_errors = $4, updated_at = $5
+		WHERE id = $6`,
+		documentStatusCompleted, parsedJSON, overallConfidence, validationJSON, time.Now(), job.id); err != nil {
+		log.Printf("Warning: failed to store result for document %d: %v", job.id, err)
+	}
 }
 
 func main() {
@@ -283,31 +608,44 @@ func main() {
 		log.Println("Loaded environment variables from .env file.")
 	}
 
-	// Get Gemini API key from environment variable
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		log.Fatal("GEMINI_API_KEY environment variable is not set")
+	// Build the LLM parser selected by LLM_PROVIDER (default "gemini"), so
+	// the backend can be swapped via environment variables alone.
+	llmProvider := os.Getenv("LLM_PROVIDER")
+
+	var llmAPIKey string
+	switch llmProvider {
+	case "", "gemini":
+		llmAPIKey = os.Getenv("GEMINI_API_KEY")
+	case "openai", "azure-openai":
+		llmAPIKey = os.Getenv("OPENAI_API_KEY")
 	}
 
-	// Initialize the Gemini client
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	parser, err := llm.New(llm.Config{
+		Provider: llmProvider,
+		APIKey:   llmAPIKey,
+		Model:    os.Getenv("LLM_MODEL"),
+		BaseURL:  os.Getenv("LLM_BASE_URL"),
+	})
 	if err != nil {
-		log.Fatalf("Failed to create Gemini client: %v", err)
+		log.Fatalf("Failed to initialize LLM provider: %v", err)
+	}
+	log.Printf("Using %s as the LLM provider.", parser.Name())
+
+	if err := registerBuiltinDocumentTypes(); err != nil {
+		log.Fatalf("Failed to register builtin document types: %v", err)
 	}
-	defer client.Close()
 
 	// Connect to the database
 	db, err := connectDB()
 	if err != nil {
 		log.Printf("Warning: Failed to connect to database: %v", err)
-		log.Println("The /finalize-parsed-fields endpoint will not be available.")
+		log.Println("The /finalize-parsed-fields and /documents endpoints will not be available.")
 		db = nil
 	} else {
 		// Initialize the database
 		if err := initDB(db); err != nil {
 			log.Printf("Warning: Failed to initialize database: %v", err)
-			log.Println("The /finalize-parsed-fields endpoint will not be available.")
+			log.Println("The /finalize-parsed-fields and /documents endpoints will not be available.")
 			db = nil
 		} else {
 			log.Println("Database connection established and initialized successfully.")
@@ -315,19 +653,32 @@ func main() {
 		defer db.Close()
 	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("POST /parse-document", func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept")
-
-		// Handle preflight OPTIONS request
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+	// documentJobs feeds background workers that parse queued /documents
+	// uploads so POST /documents can return immediately instead of blocking
+	// on the LLM call.
+	documentJobs := make(chan documentJob, documentJobQueueSize)
+	if db != nil {
+		startDocumentWorkers(db, parser, documentJobs, getEnvInt("DOCUMENT_WORKERS", defaultDocumentWorkers))
+	}
+
+	// apiKey gates POST /finalize-parsed-fields behind an X-API-Key header;
+	// it's left unset (disabling auth) if API_KEY isn't configured, so the
+	// endpoint keeps working for existing deployments that don't set it.
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		log.Println("API_KEY is not set; /finalize-parsed-fields will accept requests without authentication.")
+	}
+
+	// base is applied to every handler: a request id for end-to-end tracing,
+	// a structured access log line, and panic recovery so one bad request
+	// can't take the server down.
+	base := []httpx.Middleware{httpx.RequestID, httpx.AccessLog, httpx.Recover}
+	withMiddleware := func(h http.HandlerFunc, extra ...httpx.Middleware) http.Handler {
+		return httpx.Chain(h, append(base, extra...)...)
+	}
 
+	mux := http.NewServeMux()
+	mux.Handle("POST /parse-document", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		// Get the file from the request
 		file, header, err := r.FormFile("file")
 		if err != nil {
@@ -344,91 +695,174 @@ func main() {
 		}
 
 		// Determine the MIME type based on the file extension
-		mimeType := "application/octet-stream" // Default MIME type
-		switch {
-		case strings.HasSuffix(strings.ToLower(header.Filename), ".pdf"):
-			mimeType = "application/pdf"
-		case strings.HasSuffix(strings.ToLower(header.Filename), ".csv"):
-			mimeType = "text/csv"
-		case strings.HasSuffix(strings.ToLower(header.Filename), ".png"):
-			mimeType = "image/png"
-		case strings.HasSuffix(strings.ToLower(header.Filename), ".txt"):
-			mimeType = "text/plain"
-		case strings.HasSuffix(strings.ToLower(header.Filename), ".doc"):
-			mimeType = "application/msword"
-		case strings.HasSuffix(strings.ToLower(header.Filename), ".docx"):
-			mimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
-		}
-
-		// Determine document type from request parameter, default to Form941Type if not specified
+		mimeType := mimeTypeForFilename(header.Filename)
+
+		// Determine document type from request parameter, default to
+		// form_941 for backward compatibility. Any type registered via
+		// POST /document-types can be used here, not just the builtins.
 		docTypeStr := r.FormValue("document_type")
-		var docType DocumentType
-		switch docTypeStr {
-		case "job_details":
-			docType = JobDetailsType
-		case "form_941":
-			docType = Form941Type
-		default:
-			// Default to Form941Type for backward compatibility
-			docType = Form941Type
-		}
-
-		// Parse the document using Gemini's multimodal capabilities
-		ctx := r.Context()
-
-		// Use the appropriate type parameter based on document type
-		var parsedResult interface{}
-		var parseErr error
-
-		switch docType {
-		case JobDetailsType:
-			var result JobDetails
-			result, parseErr = parseDocumentWithGeminiMultimodal[JobDetails](ctx, client, fileContent, mimeType, docType)
-			parsedResult = result
-		case Form941Type:
-			var result Form941
-			result, parseErr = parseDocumentWithGeminiMultimodal[Form941](ctx, client, fileContent, mimeType, docType)
-			parsedResult = result
-		default:
-			http.Error(w, "Unsupported document type: "+string(docType), http.StatusBadRequest)
+		if docTypeStr == "" {
+			docTypeStr = "form_941"
+		}
+
+		dt, ok := doctype.Get(docTypeStr)
+		if !ok {
+			http.Error(w, "Unsupported document type: "+docTypeStr, http.StatusBadRequest)
 			return
 		}
 
+		// Parse the document using the configured LLM provider
+		parsedResult, overallConfidence, validationErrors, parseErr := parseDocumentFields(r.Context(), parser, fileContent, mimeType, dt)
 		if parseErr != nil {
-			// Fallback to text-based approach if multimodal approach fails
-			log.Printf("Multimodal approach failed: %v. Falling back to text-based approach.", parseErr)
+			log.Printf("Document parsing failed: %v", parseErr)
 			http.Error(w, "Error parsing document: "+parseErr.Error(), http.StatusInternalServerError)
 			return
 		}
 
 		// Return a response with the structured details
 		response := map[string]interface{}{
-			"status":        "success",
-			"message":       "Document parsed successfully",
-			"file_name":     header.Filename,
-			"file_size":     len(fileContent),
-			"document_type": docType,
-			"parsed_result": parsedResult,
+			"status":             "success",
+			"message":            "Document parsed successfully",
+			"file_name":          header.Filename,
+			"file_size":          len(fileContent),
+			"document_type":      dt.Name,
+			"parsed_result":      parsedResult,
+			"overall_confidence": overallConfidence,
+		}
+		if len(validationErrors) > 0 {
+			response["validation_errors"] = validationErrors
 		}
 
 		// Return the response as JSON
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
-	})
+	}, httpx.CORS("POST"), httpx.RateLimit(getEnvInt("RATE_LIMIT_PER_MINUTE", defaultParseRateLimit))))
 
-	// Add the finalize-parsed-fields endpoint
-	mux.HandleFunc("POST /finalize-parsed-fields", func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept")
+	// Add the document-types endpoints
+	mux.Handle("POST /document-types", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var dt doctype.Type
+		if err := json.NewDecoder(r.Body).Decode(&dt); err != nil {
+			http.Error(w, "Error parsing request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		// Handle preflight OPTIONS request
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+		if err := doctype.Register(dt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"name":   dt.Name,
+		})
+	}, httpx.CORS("POST")))
+
+	mux.Handle("GET /document-types", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"document_types": doctype.List(),
+		})
+	}, httpx.CORS("GET")))
+
+	// Add the idempotent upload/parse pipeline: POST /documents queues a
+	// parse (or returns the cached result for a file/type already parsed)
+	// and GET /documents/{id} polls for its status and result.
+	mux.Handle("POST /documents", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if db == nil {
+			http.Error(w, "Database connection is not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Error retrieving file: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		fileContent, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, "Error reading file: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		docTypeStr := r.FormValue("document_type")
+		if docTypeStr == "" {
+			docTypeStr = "form_941"
+		}
+		if _, ok := doctype.Get(docTypeStr); !ok {
+			http.Error(w, "Unsupported document type: "+docTypeStr, http.StatusBadRequest)
+			return
+		}
+
+		hash := sha256.Sum256(fileContent)
+		sha256Hash := hex.EncodeToString(hash[:])
+
+		rec, existed, err := findOrCreateDocument(db, sha256Hash, header.Filename, docTypeStr)
+		if err != nil {
+			http.Error(w, "Error creating document: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !existed {
+			documentJobs <- documentJob{
+				id:           rec.ID,
+				fileContent:  fileContent,
+				mimeType:     mimeTypeForFilename(header.Filename),
+				documentType: docTypeStr,
+			}
+		}
+
+		response := map[string]interface{}{
+			"document_id": rec.ID,
+			"status":      rec.Status,
+		}
+		if rec.Status == documentStatusCompleted {
+			response["parsed_result"] = rec.ParsedResult
+			response["overall_confidence"] = rec.OverallConfidence
+			if len(rec.ValidationErrors) > 0 {
+				response["validation_errors"] = rec.ValidationErrors
+			}
+		} else if rec.Status == documentStatusFailed {
+			response["error_message"] = rec.ErrorMessage
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !existed {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		json.NewEncoder(w).Encode(response)
+	}, httpx.CORS("POST")))
+
+	mux.Handle("GET /documents/{id}", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if db == nil {
+			http.Error(w, "Database connection is not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "Invalid document id", http.StatusBadRequest)
+			return
+		}
+
+		rec, err := scanDocumentRow(db.QueryRow(`SELECT `+documentColumns+` FROM documents WHERE id = $1`, id))
+		if err == sql.ErrNoRows {
+			http.Error(w, "Document not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Error looking up document: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rec)
+	}, httpx.CORS("GET")))
+
+	// Add the finalize-parsed-fields endpoint, gated behind APIKeyAuth since
+	// it persists caller-supplied data.
+	mux.Handle("POST /finalize-parsed-fields", withMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		// Check if database is available
 		if db == nil {
 			http.Error(w, "Database connection is not available", http.StatusServiceUnavailable)
@@ -476,7 +910,7 @@ func main() {
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(response)
-	})
+	}, httpx.CORS("POST"), httpx.APIKeyAuth(apiKey)))
 
 	// Start the server
 	port := os.Getenv("PORT")